@@ -2,28 +2,57 @@
  * dining_philosophers.go
  *
  * Solución en Go al problema de los Filósofos Comensales.
- * Se emplean sync.Mutex para cada tenedor y un "camarero" implementado
- * con un canal (buffered) que solo permite N-1 filósofos intentando
- * comer simultáneamente.
+ *
+ * Dos modos de sincronización, seleccionables con -mode:
+ *   - "waiter" (por defecto): sync.Mutex para cada tenedor y un "camarero"
+ *     implementado con un canal (buffered) que solo permite N-1 filósofos
+ *     intentando comer simultáneamente.
+ *   - "chandy": algoritmo de Chandy-Misra (tenedores con bandera "sucio"/
+ *     "limpio" que se ceden por canales punto a punto), sin camarero
+ *     central ni orden global de adquisición.
+ *
+ * Con -metrics=json|hist se conecta un Observer que reporta, para
+ * cualquiera de los dos modos, cuánto esperó cada filósofo por un
+ * tenedor y cuánta contención tuvo cada tenedor.
+ *
+ * Con -detect se activa el detector de deadlocks/starvation del paquete
+ * synctest: en modo waiter, los tenedores pasan a ser synctest.TrackedMutex
+ * en vez de sync.Mutex; en modo chandy, cada espera de tenedor también se
+ * reporta al detector. Permite verificar empíricamente que ambas
+ * soluciones son libres de deadlock y starvation para cualquier N.
  *
  * Compilar: go build dining_philosophers.go
- * Uso: ./dining_philosophers <num_philosophers> <num_ciclos_por_filosofo>
+ * Uso: ./dining_philosophers [-mode=waiter|chandy] [-metrics=none|json|hist] [-detect] <num_philosophers> <num_ciclos_por_filosofo>
  */
 
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"math/rand"
 	"os"
 	"strconv"
 	"sync"
 	"time"
+
+	"sincronizacion/observer"
+	"sincronizacion/synctest"
 )
 
 var numPhilosophers int
 var cyclesPerPhilosopher int
 
+// forkLocker es lo mínimo que necesita un filósofo de un tenedor en modo
+// "waiter": tanto Fork (sync.Mutex liso) como synctest.TrackedMutex (la
+// versión instrumentada que se activa con -detect) lo satisfacen.
+type forkLocker interface {
+	Lock()
+	Unlock()
+	TryLock() bool
+}
+
 // Cada tenedor es un mutex
 type Fork struct {
 	sync.Mutex
@@ -34,24 +63,74 @@ type Philosopher struct {
 	id       int
 	left     int
 	right    int
-	forks    []Fork
+	forks    []forkLocker
 	waiterCh chan struct{}
 	wg       *sync.WaitGroup
+	obs      observer.Observer
+	det      *synctest.Detector
+}
+
+// PhilosopherOption configura un Philosopher al crearlo.
+type PhilosopherOption func(*Philosopher)
+
+// WithPhilosopherObserver conecta un Observer que recibe las transiciones
+// de pensar/comer y las adquisiciones de tenedores del filósofo.
+func WithPhilosopherObserver(o observer.Observer) PhilosopherOption {
+	return func(p *Philosopher) { p.obs = o }
+}
+
+// WithPhilosopherDetector conecta un synctest.Detector: en modo chandy,
+// cada espera de tenedor se le reporta (en modo waiter, el detector ya va
+// conectado directamente a cada TrackedMutex).
+func WithPhilosopherDetector(d *synctest.Detector) PhilosopherOption {
+	return func(p *Philosopher) { p.det = d }
+}
+
+func newPhilosopher(id, left, right int, forks []forkLocker, waiterCh chan struct{}, wg *sync.WaitGroup, opts ...PhilosopherOption) *Philosopher {
+	p := &Philosopher{
+		id:       id,
+		left:     left,
+		right:    right,
+		forks:    forks,
+		waiterCh: waiterCh,
+		wg:       wg,
+		obs:      observer.Noop{},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
 // Filósofo piensa
 func (p *Philosopher) think() {
 	fmt.Printf("[Filósofo %d] Pensando...\n", p.id)
+	p.obs.OnThink(p.id)
 	time.Sleep(time.Duration(200+rand.Intn(200)) * time.Millisecond) // 200-400ms
 }
 
 // Filósofo come
 func (p *Philosopher) eat(cycle int) {
 	fmt.Printf("[Filósofo %d] Comiendo (ciclo %d)...\n", p.id, cycle)
+	p.obs.OnEat(p.id)
 	time.Sleep(time.Duration(250+rand.Intn(250)) * time.Millisecond) // 250-500ms
 }
 
-// Ciclo principal del filósofo
+// lockFork toma el mutex de forks[idx], reportando al Observer si tuvo
+// que esperar porque el vecino lo tenía tomado. Si forks[idx] es un
+// synctest.TrackedMutex (-detect), el propio mutex ya reporta al detector.
+func (p *Philosopher) lockFork(idx int) {
+	if p.forks[idx].TryLock() {
+		p.obs.OnForkAcquire(p.id, idx, false)
+		return
+	}
+	start := time.Now()
+	p.forks[idx].Lock()
+	p.obs.OnBlockedWait(p.id, time.Since(start))
+	p.obs.OnForkAcquire(p.id, idx, true)
+}
+
+// Ciclo principal del filósofo (modo "waiter")
 func (p *Philosopher) dine() {
 	defer p.wg.Done()
 	for i := 0; i < cyclesPerPhilosopher; i++ {
@@ -62,11 +141,11 @@ func (p *Philosopher) dine() {
 
 		// Tomar tenedores en orden (menor índice primero)
 		if p.left < p.right {
-			p.forks[p.left].Lock()
-			p.forks[p.right].Lock()
+			p.lockFork(p.left)
+			p.lockFork(p.right)
 		} else {
-			p.forks[p.right].Lock()
-			p.forks[p.left].Lock()
+			p.lockFork(p.right)
+			p.lockFork(p.left)
 		}
 
 		p.eat(i)
@@ -82,38 +161,267 @@ func (p *Philosopher) dine() {
 	fmt.Printf("[Filósofo %d] Terminó todos sus ciclos.\n", p.id)
 }
 
-func main() {
-	if len(os.Args) != 3 {
-		fmt.Printf("Uso: %s <num_philosophers> <num_ciclos_por_filosofo>\n", os.Args[0])
-		os.Exit(1)
+// --- Algoritmo de Chandy-Misra ---
+//
+// Cada tenedor lo administra su propia goroutine ("forkManager"), única
+// dueña de su estado (holder/dirty): los filósofos nunca tocan ese estado
+// directamente, solo intercambian mensajes por los canales del tenedor.
+// Así no hace falta un orden global de adquisición: el deadlock-freedom
+// sale de que, al arrancar, el dueño de cada tenedor es el filósofo de
+// menor id del par (grafo de dependencias acíclico), y de que un tenedor
+// recién cedido llega limpio y queda así hasta la próxima comida de su
+// nuevo dueño.
+
+// chandyQuery pregunta al manager de un tenedor si el filósofo philID ya lo tiene.
+type chandyQuery struct {
+	philID int
+	resp   chan bool
+}
+
+// ChandyFork es el tenedor compartido entre dos filósofos vecinos.
+type ChandyFork struct {
+	id      int
+	dirty   bool
+	holder  int
+	reqCh   chan int
+	release chan struct{}
+	query   chan chandyQuery
+	grants  map[int]chan struct{}
+}
+
+// newChandyFork crea el tenedor compartido por los filósofos a y b, con
+// dueño inicial el de menor id (grafo acíclico) y sucio, como exige el
+// protocolo de Chandy-Misra.
+func newChandyFork(id, a, b int) *ChandyFork {
+	owner := a
+	if b < a {
+		owner = b
+	}
+	return &ChandyFork{
+		id:      id,
+		dirty:   true,
+		holder:  owner,
+		reqCh:   make(chan int),
+		release: make(chan struct{}),
+		query:   make(chan chandyQuery),
+		grants: map[int]chan struct{}{
+			a: make(chan struct{}, 1),
+			b: make(chan struct{}, 1),
+		},
 	}
-	numPhilosophers, _ = strconv.Atoi(os.Args[1])
-	cyclesPerPhilosopher, _ = strconv.Atoi(os.Args[2])
+}
 
-	rand.Seed(time.Now().UnixNano())
+// run es el único lugar que lee o escribe el estado del tenedor.
+func (f *ChandyFork) run() {
+	pending := -1
+	for {
+		select {
+		case q := <-f.query:
+			q.resp <- (f.holder == q.philID)
+
+		case req := <-f.reqCh:
+			if f.holder == req {
+				// Ya era su dueño (la asignación inicial, o ninguna otra
+				// solicitud llegó desde la última comida): igual hay que
+				// marcarlo limpio aquí, antes de que coma, para que una
+				// petición entrante mientras come quede diferida (rama de
+				// abajo) en vez de concedida sobre un tenedor en uso.
+				f.dirty = false
+				f.grants[req] <- struct{}{}
+				continue
+			}
+			if f.dirty {
+				f.dirty = false
+				f.holder = req
+				f.grants[req] <- struct{}{}
+			} else {
+				// Limpio: el dueño actual se lo queda hasta volver a comer
+				// y ensuciarlo; la solicitud queda diferida.
+				pending = req
+			}
+
+		case <-f.release:
+			f.dirty = true
+			if pending != -1 {
+				req := pending
+				pending = -1
+				f.dirty = false
+				f.holder = req
+				f.grants[req] <- struct{}{}
+			}
+		}
+	}
+}
+
+// hasFork pregunta, de forma síncrona, si el filósofo ya tiene el tenedor.
+func (f *ChandyFork) hasFork(philID int) bool {
+	resp := make(chan bool, 1)
+	f.query <- chandyQuery{philID: philID, resp: resp}
+	return <-resp
+}
+
+// resourceName identifica a este tenedor frente al detector/observer.
+func (f *ChandyFork) resourceName() string {
+	return fmt.Sprintf("fork-%d", f.id)
+}
+
+// acquire solicita el tenedor y bloquea hasta que el manager lo entregue,
+// incluso si philID ya era su dueño: solo el manager puede marcarlo limpio
+// (run, arriba) antes de que el filósofo coma, así que toda adquisición
+// pasa por reqCh/grants, sin atajos por fuera de él. Reporta a obs (y, si
+// no es nil, a det) si el filósofo tuvo que esperar a que otro lo soltara.
+func (f *ChandyFork) acquire(philID int, obs observer.Observer, det *synctest.Detector) {
+	contended := !f.hasFork(philID)
+	if det != nil {
+		det.BeginWait(int64(philID), f.resourceName())
+	}
+	start := time.Now()
+	f.reqCh <- philID
+	<-f.grants[philID]
+	if det != nil {
+		det.Acquired(int64(philID), f.resourceName())
+	}
+	if contended {
+		obs.OnBlockedWait(philID, time.Since(start))
+	}
+	obs.OnForkAcquire(philID, f.id, contended)
+}
 
-	// Crear slice de tenedores
-	forks := make([]Fork, numPhilosophers)
+// dirtyAfterMeal avisa que el filósofo terminó de comer: el tenedor queda
+// sucio y, si alguien lo había pedido, se le entrega de inmediato.
+func (f *ChandyFork) dirtyAfterMeal(philID int, det *synctest.Detector) {
+	if det != nil {
+		det.Released(int64(philID), f.resourceName())
+	}
+	f.release <- struct{}{}
+}
+
+// dineChandy es el ciclo del filósofo bajo el algoritmo de Chandy-Misra.
+func (p *Philosopher) dineChandy(left, right *ChandyFork) {
+	defer p.wg.Done()
+	for i := 0; i < cyclesPerPhilosopher; i++ {
+		p.think()
 
-	// El canal del camarero tiene buffer de tamaño N-1
+		left.acquire(p.id, p.obs, p.det)
+		right.acquire(p.id, p.obs, p.det)
+
+		p.eat(i)
+
+		left.dirtyAfterMeal(p.id, p.det)
+		right.dirtyAfterMeal(p.id, p.det)
+	}
+
+	fmt.Printf("[Filósofo %d] Terminó todos sus ciclos.\n", p.id)
+}
+
+func runWaiter(obs observer.Observer, det *synctest.Detector) {
+	forks := make([]forkLocker, numPhilosophers)
+	for i := range forks {
+		if det != nil {
+			forks[i] = synctest.NewTrackedMutex(fmt.Sprintf("fork-%d", i), det)
+		} else {
+			forks[i] = &Fork{}
+		}
+	}
 	waiterCh := make(chan struct{}, numPhilosophers-1)
 
 	var wg sync.WaitGroup
-
-	// Crear e iniciar filósofos
 	for i := 0; i < numPhilosophers; i++ {
-		p := &Philosopher{
-			id:       i,
-			left:     i,
-			right:    (i + 1) % numPhilosophers,
-			forks:    forks,
-			waiterCh: waiterCh,
-			wg:       &wg,
-		}
+		p := newPhilosopher(i, i, (i+1)%numPhilosophers, forks, waiterCh, &wg, WithPhilosopherObserver(obs))
 		wg.Add(1)
 		go p.dine()
 	}
+	wg.Wait()
+}
+
+func runChandy(obs observer.Observer, det *synctest.Detector) {
+	forks := make([]*ChandyFork, numPhilosophers)
+	for i := 0; i < numPhilosophers; i++ {
+		// El tenedor i es el derecho del filósofo i-1 y el izquierdo del
+		// filósofo i (misma convención que left/right en Philosopher), así
+		// sus dos miembros son exactamente sus dos vecinos.
+		prev := (i - 1 + numPhilosophers) % numPhilosophers
+		forks[i] = newChandyFork(i, prev, i)
+		go forks[i].run()
+	}
 
+	var wg sync.WaitGroup
+	for i := 0; i < numPhilosophers; i++ {
+		p := newPhilosopher(i, i, (i+1)%numPhilosophers, nil, nil, &wg, WithPhilosopherObserver(obs), WithPhilosopherDetector(det))
+		wg.Add(1)
+		go p.dineChandy(forks[p.left], forks[p.right])
+	}
 	wg.Wait()
+}
+
+// buildObserver construye el Observer pedido por -metrics.
+func buildObserver(kind string) observer.Observer {
+	switch kind {
+	case "json":
+		return observer.NewJSONLogger(os.Stdout)
+	case "hist":
+		return observer.NewHistogramCollector()
+	default:
+		return observer.Noop{}
+	}
+}
+
+// reportIssues imprime un reporte del detector solo cuando hay algo que
+// contar: un ciclo de deadlock o alguna goroutine en starvation.
+func reportIssues(r synctest.Report) {
+	if len(r.Deadlocks) == 0 && len(r.Starving) == 0 {
+		return
+	}
+	fmt.Printf("[detect] %s sin progreso\n", r.Idle.Round(time.Millisecond))
+	for _, cycle := range r.Deadlocks {
+		fmt.Printf("[detect] DEADLOCK: ciclo de espera entre goroutines %v\n", cycle)
+	}
+	for _, gid := range r.Starving {
+		fmt.Printf("[detect] STARVATION: goroutine %d lleva esperando mientras otras progresan\n", gid)
+	}
+}
+
+func main() {
+	mode := flag.String("mode", "waiter", "algoritmo de sincronización: waiter | chandy")
+	metrics := flag.String("metrics", "none", "observador de filósofos/tenedores: none | json | hist")
+	detect := flag.Bool("detect", false, "activa el detector de deadlocks/starvation de synctest")
+	quiescence := flag.Duration("quiescence", time.Second, "tiempo sin progreso antes de evaluar deadlocks/starvation (con -detect)")
+	starveAfter := flag.Duration("starve-after", 2*time.Second, "cuánto puede esperar una goroutine antes de considerarse hambrienta (con -detect)")
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) != 2 {
+		fmt.Printf("Uso: %s [-mode=waiter|chandy] [-metrics=none|json|hist] [-detect] <num_philosophers> <num_ciclos_por_filosofo>\n", os.Args[0])
+		os.Exit(1)
+	}
+	numPhilosophers, _ = strconv.Atoi(args[0])
+	cyclesPerPhilosopher, _ = strconv.Atoi(args[1])
+
+	rand.Seed(time.Now().UnixNano())
+
+	obs := buildObserver(*metrics)
+
+	var det *synctest.Detector
+	if *detect {
+		det = synctest.NewDetector(*quiescence, *starveAfter)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go det.Run(ctx, reportIssues)
+	}
+
+	switch *mode {
+	case "waiter":
+		runWaiter(obs, det)
+	case "chandy":
+		runChandy(obs, det)
+	default:
+		fmt.Printf("Modo desconocido: %s (use waiter|chandy)\n", *mode)
+		os.Exit(1)
+	}
+
 	fmt.Println("Todos los filósofos han terminado.")
+
+	if hist, ok := obs.(*observer.HistogramCollector); ok {
+		fmt.Print(hist.Report())
+	}
 }
@@ -0,0 +1,110 @@
+package observer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type waitStats struct {
+	count int
+	total time.Duration
+	max   time.Duration
+}
+
+// HistogramCollector agrega en memoria el tiempo de espera por worker, la
+// distribución de profundidad de la cola y la contención por tenedor, de
+// forma que al cerrar el programa se pueda comparar empíricamente el
+// costo de cada estrategia de sincronización (mutex/cond, semáforo,
+// Chandy-Misra).
+type HistogramCollector struct {
+	mu             sync.Mutex
+	waitByWorker   map[int]*waitStats
+	queueDepth     map[int]int // profundidad observada -> veces vista
+	forkContention map[int]int // forkID -> adquisiciones que tuvieron que esperar
+}
+
+// NewHistogramCollector crea un recolector vacío.
+func NewHistogramCollector() *HistogramCollector {
+	return &HistogramCollector{
+		waitByWorker:   make(map[int]*waitStats),
+		queueDepth:     make(map[int]int),
+		forkContention: make(map[int]int),
+	}
+}
+
+func (h *HistogramCollector) OnEnqueue(_, queueLen int) { h.recordDepth(queueLen) }
+func (h *HistogramCollector) OnDequeue(_, queueLen int) { h.recordDepth(queueLen) }
+
+func (h *HistogramCollector) recordDepth(depth int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.queueDepth[depth]++
+}
+
+func (h *HistogramCollector) OnBlockedWait(workerID int, wait time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	st := h.waitByWorker[workerID]
+	if st == nil {
+		st = &waitStats{}
+		h.waitByWorker[workerID] = st
+	}
+	st.count++
+	st.total += wait
+	if wait > st.max {
+		st.max = wait
+	}
+}
+
+func (h *HistogramCollector) OnForkAcquire(_, forkID int, contended bool) {
+	if !contended {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.forkContention[forkID]++
+}
+
+func (h *HistogramCollector) OnEat(int)   {}
+func (h *HistogramCollector) OnThink(int) {}
+
+// Report arma un resumen legible de lo acumulado hasta el momento: se
+// pensó para llamarse al final del programa (por eso el formato es un
+// texto ya listo para imprimir, no datos crudos).
+func (h *HistogramCollector) Report() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var b strings.Builder
+
+	fmt.Fprintln(&b, "--- Tiempo de espera por worker ---")
+	for _, id := range sortedKeys(h.waitByWorker) {
+		st := h.waitByWorker[id]
+		avg := st.total / time.Duration(st.count)
+		fmt.Fprintf(&b, "  worker %d: %d esperas, promedio %s, máximo %s\n", id, st.count, avg, st.max)
+	}
+
+	fmt.Fprintln(&b, "--- Distribución de profundidad de la cola ---")
+	for _, depth := range sortedKeys(h.queueDepth) {
+		fmt.Fprintf(&b, "  profundidad %d: %d veces\n", depth, h.queueDepth[depth])
+	}
+
+	fmt.Fprintln(&b, "--- Contención por tenedor ---")
+	for _, fork := range sortedKeys(h.forkContention) {
+		fmt.Fprintf(&b, "  tenedor %d: %d adquisiciones con espera\n", fork, h.forkContention[fork])
+	}
+
+	return b.String()
+}
+
+func sortedKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
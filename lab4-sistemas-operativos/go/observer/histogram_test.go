@@ -0,0 +1,43 @@
+package observer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistogramCollectorAggregatesWaitTimes(t *testing.T) {
+	h := NewHistogramCollector()
+	h.OnBlockedWait(1, 10*time.Millisecond)
+	h.OnBlockedWait(1, 30*time.Millisecond)
+	h.OnBlockedWait(2, 5*time.Millisecond)
+
+	report := h.Report()
+	if !strings.Contains(report, "worker 1: 2 esperas") {
+		t.Fatalf("el reporte debería resumir 2 esperas del worker 1, obtuve:\n%s", report)
+	}
+	if !strings.Contains(report, "worker 2: 1 esperas") {
+		t.Fatalf("el reporte debería resumir 1 espera del worker 2, obtuve:\n%s", report)
+	}
+}
+
+func TestHistogramCollectorQueueDepthAndForkContention(t *testing.T) {
+	h := NewHistogramCollector()
+	h.OnEnqueue(0, 1)
+	h.OnEnqueue(0, 2)
+	h.OnDequeue(0, 1)
+	h.OnForkAcquire(0, 3, true)
+	h.OnForkAcquire(0, 3, false)
+	h.OnForkAcquire(0, 4, true)
+
+	report := h.Report()
+	if !strings.Contains(report, "profundidad 1: 2 veces") {
+		t.Fatalf("esperaba profundidad 1 vista 2 veces, obtuve:\n%s", report)
+	}
+	if !strings.Contains(report, "tenedor 3: 1 adquisiciones con espera") {
+		t.Fatalf("esperaba 1 adquisición con espera para el tenedor 3, obtuve:\n%s", report)
+	}
+	if strings.Contains(report, "tenedor 4: 0") {
+		t.Fatalf("el tenedor 4 sí tuvo contención, no debería reportarse como 0:\n%s", report)
+	}
+}
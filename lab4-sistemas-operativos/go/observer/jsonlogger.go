@@ -0,0 +1,69 @@
+package observer
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// jsonEvent es la forma serializada de cada evento; los campos que no
+// aplican al evento quedan vacíos gracias a omitempty.
+type jsonEvent struct {
+	Time      string  `json:"time"`
+	Event     string  `json:"event"`
+	WorkerID  int     `json:"worker_id"`
+	ForkID    int     `json:"fork_id,omitempty"`
+	QueueLen  int     `json:"queue_len,omitempty"`
+	WaitMS    float64 `json:"wait_ms,omitempty"`
+	Contended bool    `json:"contended,omitempty"`
+}
+
+// JSONLogger vuelca cada evento como una línea JSON independiente en w.
+// Es seguro para uso concurrente.
+type JSONLogger struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONLogger crea un JSONLogger que escribe en w.
+func NewJSONLogger(w io.Writer) *JSONLogger {
+	return &JSONLogger{w: w}
+}
+
+func (l *JSONLogger) write(e jsonEvent) {
+	e.Time = time.Now().Format(time.RFC3339Nano)
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.w.Write(data)
+}
+
+func (l *JSONLogger) OnEnqueue(workerID, queueLen int) {
+	l.write(jsonEvent{Event: "enqueue", WorkerID: workerID, QueueLen: queueLen})
+}
+
+func (l *JSONLogger) OnDequeue(workerID, queueLen int) {
+	l.write(jsonEvent{Event: "dequeue", WorkerID: workerID, QueueLen: queueLen})
+}
+
+func (l *JSONLogger) OnBlockedWait(workerID int, wait time.Duration) {
+	l.write(jsonEvent{Event: "blocked_wait", WorkerID: workerID, WaitMS: float64(wait) / float64(time.Millisecond)})
+}
+
+func (l *JSONLogger) OnForkAcquire(philosopherID, forkID int, contended bool) {
+	l.write(jsonEvent{Event: "fork_acquire", WorkerID: philosopherID, ForkID: forkID, Contended: contended})
+}
+
+func (l *JSONLogger) OnEat(philosopherID int) {
+	l.write(jsonEvent{Event: "eat", WorkerID: philosopherID})
+}
+
+func (l *JSONLogger) OnThink(philosopherID int) {
+	l.write(jsonEvent{Event: "think", WorkerID: philosopherID})
+}
@@ -0,0 +1,47 @@
+/*
+ * observer.go
+ *
+ * Interfaz de observación para instrumentar las estructuras de
+ * sincronización del proyecto (colas, buffer circular, filósofos) sin
+ * acoplarlas a una implementación concreta de métricas. Antes, esas
+ * estructuras solo dejaban rastro con fmt.Printf; con Observer se puede
+ * conectar un logger estructurado, un agregador en memoria, o ambos.
+ */
+package observer
+
+import "time"
+
+// Observer recibe los eventos de sincronización que emiten las colas,
+// el buffer circular y los filósofos. Los identificadores (workerID,
+// philosopherID, forkID) son lógicos: el id de productor/consumidor o de
+// filósofo asignado por el programa, no un id de goroutine del runtime.
+// Quien emite el evento sin conocer esa identidad (la cola de tsqueue)
+// reporta workerID -1.
+type Observer interface {
+	// OnEnqueue se dispara cuando se agrega un elemento; queueLen es la
+	// cantidad de elementos tras la operación.
+	OnEnqueue(workerID, queueLen int)
+	// OnDequeue se dispara cuando se retira un elemento; queueLen es la
+	// cantidad de elementos tras la operación.
+	OnDequeue(workerID, queueLen int)
+	// OnBlockedWait se dispara cuando un worker tuvo que esperar (cola
+	// vacía/llena, tenedor ocupado) antes de poder avanzar.
+	OnBlockedWait(workerID int, wait time.Duration)
+	// OnForkAcquire se dispara cuando un filósofo obtiene un tenedor.
+	// contended indica si tuvo que esperar por él.
+	OnForkAcquire(philosopherID, forkID int, contended bool)
+	// OnEat y OnThink marcan las transiciones de estado del filósofo.
+	OnEat(philosopherID int)
+	OnThink(philosopherID int)
+}
+
+// Noop implementa Observer sin hacer nada; es el valor por defecto
+// cuando ninguna opción WithObserver especifica uno.
+type Noop struct{}
+
+func (Noop) OnEnqueue(int, int)               {}
+func (Noop) OnDequeue(int, int)               {}
+func (Noop) OnBlockedWait(int, time.Duration) {}
+func (Noop) OnForkAcquire(int, int, bool)     {}
+func (Noop) OnEat(int)                        {}
+func (Noop) OnThink(int)                      {}
@@ -2,81 +2,93 @@
  * producer_consumer.go
  *
  * Problema Productor‐Consumidor con buffer acotado en Go.
- * Se implementa un semáforo simple usando canales.
+ * El conteo de espacios libres/elementos disponibles lo lleva el semáforo
+ * con peso del paquete syncx en vez de un canal hecho a mano.
  *
  * Compilar: go build producer_consumer.go
- * Uso: ./producer_consumer <num_producers> <num_consumers> <buffer_size> <items_per_producer>
+ * Uso: ./producer_consumer [-timeout=ms] [-metrics=none|json|hist] <num_producers> <num_consumers> <buffer_size> <items_per_producer>
  */
 
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"math/rand"
 	"os"
 	"strconv"
 	"sync"
 	"time"
+
+	"sincronizacion/observer"
+	"sincronizacion/syncx"
 )
 
 // Buffer circular
 type CircularBuffer struct {
-	data       []int
-	size       int
-	in, out    int
-	lock       sync.Mutex
+	data    []int
+	size    int
+	in, out int
+	count   int
+	lock    sync.Mutex
+	obs     observer.Observer
+}
+
+// Option configura un CircularBuffer al crearlo.
+type Option func(*CircularBuffer)
+
+// WithObserver conecta un Observer que recibe los eventos del buffer
+// (Put -> OnEnqueue, Get -> OnDequeue).
+func WithObserver(o observer.Observer) Option {
+	return func(b *CircularBuffer) { b.obs = o }
 }
 
 // Inicializar buffer con tamaño n
-func NewBuffer(n int) *CircularBuffer {
-	return &CircularBuffer{
+func NewBuffer(n int, opts ...Option) *CircularBuffer {
+	b := &CircularBuffer{
 		data: make([]int, n),
 		size: n,
-		in:   0,
-		out:  0,
+		obs:  observer.Noop{},
+	}
+	for _, opt := range opts {
+		opt(b)
 	}
+	return b
 }
 
 // Escribir en posición 'in'
-func (b *CircularBuffer) Put(item int) {
+func (b *CircularBuffer) Put(workerID, item int) {
 	b.lock.Lock()
 	b.data[b.in] = item
 	fmt.Printf("[Producer] puso %d en buffer[%d]\n", item, b.in)
 	b.in = (b.in + 1) % b.size
+	b.count++
+	depth := b.count
 	b.lock.Unlock()
+
+	b.obs.OnEnqueue(workerID, depth)
 }
 
 // Leer de posición 'out'
-func (b *CircularBuffer) Get() int {
+func (b *CircularBuffer) Get(workerID int) int {
 	b.lock.Lock()
 	item := b.data[b.out]
 	fmt.Printf("[Consumer] tomó %d del buffer[%d]\n", item, b.out)
 	b.out = (b.out + 1) % b.size
+	b.count--
+	depth := b.count
 	b.lock.Unlock()
-	return item
-}
-
-// Semáforo simple basado en canal con a capacidad 'n' para contar recursos
-type Semaphore chan struct{}
 
-func NewSemaphore(n int) Semaphore {
-	return make(Semaphore, n)
-}
-
-// Wait: recibir de canal decrementa contador; si canal está vacío, bloquea hasta haber valor
-func (s Semaphore) Wait() {
-	<-s
-}
-
-// Signal: enviar al canal incrementa contador; si canal está lleno, bloquea o panic (según uso)
-func (s Semaphore) Signal() {
-	s <- struct{}{}
+	b.obs.OnDequeue(workerID, depth)
+	return item
 }
 
 var (
-	buffer             *CircularBuffer
-	semEmpty, semFull  Semaphore
-	itemsPerProducer   int
+	buffer            *CircularBuffer
+	semEmpty, semFull *syncx.Semaphore
+	itemsPerProducer  int
+	timeoutMs         int
 )
 
 // Simula producción de ítem
@@ -93,67 +105,107 @@ func producer(id int, wg *sync.WaitGroup) {
 	defer wg.Done()
 	for i := 0; i < itemsPerProducer; i++ {
 		item := produceItem()
-		// Esperar espacio vacío
-		semEmpty.Wait()
-		buffer.Put(item)
+
+		ctx := context.Background()
+		var cancel context.CancelFunc
+		if timeoutMs > 0 {
+			ctx, cancel = context.WithTimeout(ctx, time.Duration(timeoutMs)*time.Millisecond)
+		}
+		// Esperar espacio vacío (cancelable si se configuró -timeout)
+		err := semEmpty.AcquireContext(ctx, 1)
+		if cancel != nil {
+			cancel()
+		}
+		if err != nil {
+			fmt.Printf("[Producer %d] buffer lleno tras %dms, descarta el item %d\n", id, timeoutMs, item)
+			continue
+		}
+
+		buffer.Put(id, item)
 		// Indicar que hay elemento disponible
-		semFull.Signal()
+		semFull.Release(1)
 		time.Sleep(100 * time.Millisecond)
 	}
 }
 
-func consumer(id int, wg *sync.WaitGroup) {
+func consumer(id int, wg *sync.WaitGroup, ctx context.Context) {
 	defer wg.Done()
 	for {
-		// Esperar elemento disponible
-		semFull.Wait()
-		item := buffer.Get()
+		// Esperar elemento disponible, o salir si se canceló el contexto
+		// (los productores ya terminaron y no va a llegar nada más).
+		if err := semFull.AcquireContext(ctx, 1); err != nil {
+			return
+		}
+		item := buffer.Get(id)
 		// Liberar espacio
-		semEmpty.Signal()
+		semEmpty.Release(1)
 		consumeItem(item)
-		// En este ejemplo, el consumidor no deja de correr a menos que se aborte
+	}
+}
+
+// buildObserver construye el Observer pedido por -metrics.
+func buildObserver(kind string) observer.Observer {
+	switch kind {
+	case "json":
+		return observer.NewJSONLogger(os.Stdout)
+	case "hist":
+		return observer.NewHistogramCollector()
+	default:
+		return observer.Noop{}
 	}
 }
 
 func main() {
-	if len(os.Args) != 5 {
-		fmt.Printf("Uso: %s <num_producers> <num_consumers> <buffer_size> <items_per_producer>\n", os.Args[0])
+	flag.IntVar(&timeoutMs, "timeout", 0, "milisegundos que un productor espera por espacio libre antes de descartar el item (0 = sin límite)")
+	metrics := flag.String("metrics", "none", "observador del buffer: none | json | hist")
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) != 4 {
+		fmt.Printf("Uso: %s [-timeout=ms] [-metrics=none|json|hist] <num_producers> <num_consumers> <buffer_size> <items_per_producer>\n", os.Args[0])
 		os.Exit(1)
 	}
 
-	numProducers, _ := strconv.Atoi(os.Args[1])
-	numConsumers, _ := strconv.Atoi(os.Args[2])
-	bufferSize, _ := strconv.Atoi(os.Args[3])
-	itemsPerProducer, _ = strconv.Atoi(os.Args[4])
+	numProducers, _ := strconv.Atoi(args[0])
+	numConsumers, _ := strconv.Atoi(args[1])
+	bufferSize, _ := strconv.Atoi(args[2])
+	itemsPerProducer, _ = strconv.Atoi(args[3])
 
 	rand.Seed(time.Now().UnixNano())
 
-	buffer = NewBuffer(bufferSize)
-	semEmpty = NewSemaphore(bufferSize)
-	semFull = NewSemaphore(0)
-	// Inicializar semEmpty con 'bufferSize' tokens
-	for i := 0; i < bufferSize; i++ {
-		semEmpty.Signal()
-	}
+	obs := buildObserver(*metrics)
+	buffer = NewBuffer(bufferSize, WithObserver(obs))
+	// semEmpty arranca con 'bufferSize' permisos: tantos huecos libres hay.
+	semEmpty = syncx.NewSemaphore(int64(bufferSize))
+	// semFull arranca en 0 disponibles: el buffer todavía no tiene elementos.
+	semFull = syncx.NewSemaphore(int64(bufferSize))
+	semFull.Acquire(int64(bufferSize))
 
-	var wg sync.WaitGroup
+	var producersWg, consumersWg sync.WaitGroup
+	consumerCtx, stopConsumers := context.WithCancel(context.Background())
+	defer stopConsumers()
 
-	// Iniciar consumidores (quedarán bloqueados en semFull.Wait() hasta que haya elementos)
+	// Iniciar consumidores (quedarán bloqueados en semFull.AcquireContext hasta
+	// que haya elementos o se cancele consumerCtx)
 	for i := 0; i < numConsumers; i++ {
-		wg.Add(1)
-		go consumer(i, &wg)
+		consumersWg.Add(1)
+		go consumer(i, &consumersWg, consumerCtx)
 	}
 
 	// Iniciar productores
 	for i := 0; i < numProducers; i++ {
-		wg.Add(1)
-		go producer(i, &wg)
+		producersWg.Add(1)
+		go producer(i, &producersWg)
 	}
 
-	// Esperar a productores
-	wg.Wait()
-
-	// Tras terminar productores, los consumidores siguen vivos; se espera unos segundos y se sale
-	time.Sleep(2 * time.Second)
+	// Esperar a productores y, una vez que ya no va a llegar nada más,
+	// avisar a los consumidores para que dejen de esperar y terminen.
+	producersWg.Wait()
+	stopConsumers()
+	consumersWg.Wait()
 	fmt.Println("Productores terminaron. Fin del programa.")
+
+	if hist, ok := obs.(*observer.HistogramCollector); ok {
+		fmt.Print(hist.Report())
+	}
 }
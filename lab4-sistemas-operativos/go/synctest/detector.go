@@ -0,0 +1,150 @@
+/*
+ * detector.go
+ *
+ * Detector de deadlocks y starvation. Cada vez que una goroutine empieza
+ * a esperar un recurso (BeginWait), lo consigue (Acquired) o lo suelta
+ * (Released), el Detector actualiza un grafo de espera: una arista
+ * waiter -> holder por cada goroutine bloqueada en un recurso que otra
+ * ya tiene. Tras una ventana de quiescencia sin progreso, Run vuelca ese
+ * grafo y corre Tarjan para reportar ciclos (deadlocks) y goroutines que
+ * llevan esperando más que el umbral mientras otras sí avanzaron
+ * (starvation).
+ */
+package synctest
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type waitInfo struct {
+	resource        string
+	since           time.Time
+	progressAtStart int64
+}
+
+// Report resume el estado del grafo de espera en el momento en que se
+// generó.
+type Report struct {
+	// Deadlocks contiene, por cada ciclo encontrado, los ids de goroutine
+	// que lo forman.
+	Deadlocks [][]int64
+	// Starving contiene los ids de goroutine que esperan desde hace más
+	// del umbral configurado mientras el resto del sistema progresaba.
+	Starving []int64
+	// Idle es cuánto tiempo pasó desde la última adquisición registrada.
+	Idle time.Duration
+}
+
+// Detector acumula, de forma segura para concurrencia, quién tiene cada
+// recurso y quién espera por cuál.
+type Detector struct {
+	mu            sync.Mutex
+	holders       map[string]int64
+	waiting       map[int64]waitInfo
+	progressCount int64
+	lastProgress  time.Time
+	quiescence    time.Duration
+	starveAfter   time.Duration
+}
+
+// NewDetector crea un detector. quiescence es cuánto tiempo sin progreso
+// hace falta para considerar al sistema estancado y generar un reporte;
+// starveAfter es cuánto puede esperar una goroutine, mientras otras
+// avanzan, antes de marcarla como hambrienta.
+func NewDetector(quiescence, starveAfter time.Duration) *Detector {
+	return &Detector{
+		holders:      make(map[string]int64),
+		waiting:      make(map[int64]waitInfo),
+		lastProgress: time.Now(),
+		quiescence:   quiescence,
+		starveAfter:  starveAfter,
+	}
+}
+
+// BeginWait registra que gid empezó a esperar resource.
+func (d *Detector) BeginWait(gid int64, resource string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.waiting[gid] = waitInfo{resource: resource, since: time.Now(), progressAtStart: d.progressCount}
+}
+
+// Acquired registra que gid obtuvo resource: deja de esperar, pasa a ser
+// su dueño, y cuenta como progreso del sistema.
+func (d *Detector) Acquired(gid int64, resource string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.waiting, gid)
+	d.holders[resource] = gid
+	d.progressCount++
+	d.lastProgress = time.Now()
+}
+
+// Released registra que gid soltó resource. No hace nada si gid ya no
+// figuraba como dueño (evita una carrera con un Acquired posterior de
+// otra goroutine sobre el mismo recurso).
+func (d *Detector) Released(gid int64, resource string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.holders[resource] == gid {
+		delete(d.holders, resource)
+	}
+}
+
+// Check arma un Report a partir del estado actual, sin esperar a la
+// ventana de quiescencia.
+func (d *Detector) Check() Report {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	graph := make(map[int64][]int64, len(d.waiting))
+	for gid, info := range d.waiting {
+		if holder, ok := d.holders[info.resource]; ok && holder != gid {
+			graph[gid] = append(graph[gid], holder)
+		}
+	}
+
+	var deadlocks [][]int64
+	for _, scc := range tarjanSCC(graph) {
+		if len(scc) > 1 {
+			deadlocks = append(deadlocks, scc)
+		}
+	}
+
+	now := time.Now()
+	var starving []int64
+	for gid, info := range d.waiting {
+		if now.Sub(info.since) > d.starveAfter && info.progressAtStart < d.progressCount {
+			starving = append(starving, gid)
+		}
+	}
+
+	return Report{Deadlocks: deadlocks, Starving: starving, Idle: now.Sub(d.lastProgress)}
+}
+
+// Run sondea periódicamente el detector y, cada vez que el sistema lleva
+// al menos quiescence sin progreso, llama a onQuiescent con el Report
+// resultante. Vuelve cuando ctx se cancela.
+func (d *Detector) Run(ctx context.Context, onQuiescent func(Report)) {
+	interval := d.quiescence / 4
+	if interval <= 0 {
+		interval = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.mu.Lock()
+			idle := time.Since(d.lastProgress)
+			d.mu.Unlock()
+			if idle >= d.quiescence {
+				onQuiescent(d.Check())
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package synctest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTarjanSCCFindsCycle(t *testing.T) {
+	// 1 -> 2 -> 3 -> 1 es un ciclo; 4 -> 1 no cierra ninguno nuevo.
+	graph := map[int64][]int64{
+		1: {2},
+		2: {3},
+		3: {1},
+		4: {1},
+	}
+	sccs := tarjanSCC(graph)
+
+	var found bool
+	for _, scc := range sccs {
+		if len(scc) == 3 {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("esperaba una componente de 3 nodos (1,2,3), obtuve %v", sccs)
+	}
+}
+
+func TestDetectorReportsDeadlockCycle(t *testing.T) {
+	d := NewDetector(time.Second, time.Second)
+
+	// g1 tiene A y espera B; g2 tiene B y espera A: ciclo.
+	d.Acquired(1, "A")
+	d.Acquired(2, "B")
+	d.BeginWait(1, "B")
+	d.BeginWait(2, "A")
+
+	report := d.Check()
+	if len(report.Deadlocks) != 1 {
+		t.Fatalf("esperaba 1 ciclo de deadlock, obtuve %d: %v", len(report.Deadlocks), report.Deadlocks)
+	}
+}
+
+func TestDetectorNoFalsePositiveWithoutCycle(t *testing.T) {
+	d := NewDetector(time.Second, time.Second)
+
+	d.Acquired(1, "A")
+	d.BeginWait(2, "A") // g2 espera a g1, pero g1 no espera a nadie
+
+	report := d.Check()
+	if len(report.Deadlocks) != 0 {
+		t.Fatalf("no esperaba deadlocks, obtuve %v", report.Deadlocks)
+	}
+}
+
+func TestDetectorFlagsStarvation(t *testing.T) {
+	d := NewDetector(time.Second, 10*time.Millisecond)
+
+	d.BeginWait(1, "A")
+	// Otra goroutine progresa mientras la 1 sigue esperando.
+	d.Acquired(2, "B")
+	time.Sleep(20 * time.Millisecond)
+
+	report := d.Check()
+	if len(report.Starving) != 1 || report.Starving[0] != 1 {
+		t.Fatalf("esperaba que la goroutine 1 apareciera como hambrienta, obtuve %v", report.Starving)
+	}
+}
+
+func TestReleasedIgnoresStaleOwner(t *testing.T) {
+	d := NewDetector(time.Second, time.Second)
+
+	d.Acquired(1, "A")
+	d.Acquired(2, "A") // g2 "roba" el recurso (simula una adquisición más reciente)
+	d.Released(1, "A") // g1 suelta tarde: no debe borrar la marca de g2
+
+	// Si "A" siguiera marcado como libre, este ciclo (g2 <-> g3) no se vería.
+	d.Acquired(3, "B")
+	d.BeginWait(2, "B")
+	d.BeginWait(3, "A")
+
+	report := d.Check()
+	if len(report.Deadlocks) != 1 {
+		t.Fatalf("esperaba detectar el ciclo g2<->g3 (A seguía en manos de g2), obtuve %v", report.Deadlocks)
+	}
+}
@@ -0,0 +1,27 @@
+package synctest
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// GoroutineID devuelve el id de la goroutine que llama, extraído del
+// encabezado de su propio stack trace ("goroutine 123 [running]: ...").
+// Go no expone este id de forma oficial; este truco es solo para
+// diagnóstico (identificar quién espera qué en el detector), nunca para
+// lógica de negocio.
+func GoroutineID() int64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+
+	fields := bytes.Fields(buf)
+	if len(fields) < 2 {
+		return -1
+	}
+	id, err := strconv.ParseInt(string(fields[1]), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return id
+}
@@ -0,0 +1,77 @@
+package synctest
+
+import "sort"
+
+// tarjanSCC calcula las componentes fuertemente conexas de graph con el
+// algoritmo de Tarjan. Una componente con más de un nodo (o un nodo con
+// un lazo hacia sí mismo) es un ciclo en el grafo de espera, es decir,
+// un deadlock.
+func tarjanSCC(graph map[int64][]int64) [][]int64 {
+	f := &sccFinder{
+		graph:   graph,
+		index:   make(map[int64]int),
+		low:     make(map[int64]int),
+		onStack: make(map[int64]bool),
+	}
+
+	nodes := make([]int64, 0, len(graph))
+	for n := range graph {
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i] < nodes[j] })
+
+	for _, n := range nodes {
+		if _, visited := f.index[n]; !visited {
+			f.strongConnect(n)
+		}
+	}
+	return f.result
+}
+
+type sccFinder struct {
+	graph   map[int64][]int64
+	index   map[int64]int
+	low     map[int64]int
+	onStack map[int64]bool
+	stack   []int64
+	counter int
+	result  [][]int64
+}
+
+func (f *sccFinder) strongConnect(v int64) {
+	f.index[v] = f.counter
+	f.low[v] = f.counter
+	f.counter++
+	f.stack = append(f.stack, v)
+	f.onStack[v] = true
+
+	for _, w := range f.graph[v] {
+		if _, visited := f.index[w]; !visited {
+			f.strongConnect(w)
+			if f.low[w] < f.low[v] {
+				f.low[v] = f.low[w]
+			}
+		} else if f.onStack[w] {
+			if f.index[w] < f.low[v] {
+				f.low[v] = f.index[w]
+			}
+		}
+	}
+
+	if f.low[v] != f.index[v] {
+		return
+	}
+
+	var scc []int64
+	for {
+		n := len(f.stack) - 1
+		w := f.stack[n]
+		f.stack = f.stack[:n]
+		f.onStack[w] = false
+		scc = append(scc, w)
+		if w == v {
+			break
+		}
+	}
+	f.result = append(f.result, scc)
+}
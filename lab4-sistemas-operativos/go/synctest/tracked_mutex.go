@@ -0,0 +1,42 @@
+package synctest
+
+import "sync"
+
+// TrackedMutex es un sync.Mutex que reporta sus eventos de lock/unlock a
+// un Detector, identificado por un nombre de recurso (p. ej. "fork-3").
+type TrackedMutex struct {
+	mu       sync.Mutex
+	resource string
+	det      *Detector
+}
+
+// NewTrackedMutex crea un mutex instrumentado para resource, reportando
+// al detector det.
+func NewTrackedMutex(resource string, det *Detector) *TrackedMutex {
+	return &TrackedMutex{resource: resource, det: det}
+}
+
+// Lock toma el mutex, registrando en el detector el inicio de la espera de
+// la goroutine llamante y, al obtenerlo, la adquisición.
+func (m *TrackedMutex) Lock() {
+	gid := GoroutineID()
+	m.det.BeginWait(gid, m.resource)
+	m.mu.Lock()
+	m.det.Acquired(gid, m.resource)
+}
+
+// TryLock intenta tomar el mutex sin bloquear.
+func (m *TrackedMutex) TryLock() bool {
+	if !m.mu.TryLock() {
+		return false
+	}
+	m.det.Acquired(GoroutineID(), m.resource)
+	return true
+}
+
+// Unlock suelta el mutex.
+func (m *TrackedMutex) Unlock() {
+	gid := GoroutineID()
+	m.mu.Unlock()
+	m.det.Released(gid, m.resource)
+}
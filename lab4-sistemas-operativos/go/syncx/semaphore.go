@@ -0,0 +1,121 @@
+/*
+ * semaphore.go
+ *
+ * Semáforo contador con peso (weighted), con espera cancelable por
+ * contexto y una cola de espera FIFO para garantizar equidad: el primer
+ * goroutine en pedir permisos es el primero en recibirlos, aunque una
+ * petición más pequeña detrás de él sí cupiera en el hueco libre.
+ */
+package syncx
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+type waiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+// Semaphore es un semáforo contador de capacidad fija que admite
+// adquirir/liberar más de un permiso a la vez.
+type Semaphore struct {
+	mu      sync.Mutex
+	size    int64
+	cur     int64
+	waiters list.List
+}
+
+// NewSemaphore crea un semáforo con capacity permisos, todos disponibles.
+func NewSemaphore(capacity int64) *Semaphore {
+	return &Semaphore{size: capacity}
+}
+
+// Acquire bloquea hasta obtener n permisos.
+func (s *Semaphore) Acquire(n int64) {
+	// context.Background() nunca se cancela, así que el error siempre es nil.
+	_ = s.AcquireContext(context.Background(), n)
+}
+
+// TryAcquire intenta tomar n permisos sin bloquear. Devuelve false si no
+// hay suficientes disponibles en este instante (o si hay otros esperando
+// en la cola, para no saltárselos).
+func (s *Semaphore) TryAcquire(n int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		return true
+	}
+	return false
+}
+
+// AcquireContext bloquea hasta obtener n permisos o hasta que ctx se
+// cancele, en cuyo caso devuelve ctx.Err() y no adquiere nada.
+func (s *Semaphore) AcquireContext(ctx context.Context, n int64) error {
+	s.mu.Lock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.mu.Unlock()
+		return nil
+	}
+
+	ready := make(chan struct{})
+	elem := s.waiters.PushBack(waiter{n: n, ready: ready})
+	s.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		err := ctx.Err()
+		s.mu.Lock()
+		select {
+		case <-ready:
+			// Se concedió justo cuando se cancelaba el contexto: nos
+			// quedamos con el resultado exitoso en vez de descartarlo.
+			err = nil
+		default:
+			wasFront := s.waiters.Front() == elem
+			s.waiters.Remove(elem)
+			if wasFront {
+				s.notifyWaitersLocked()
+			}
+		}
+		s.mu.Unlock()
+		return err
+	}
+}
+
+// Release libera n permisos previamente adquiridos.
+func (s *Semaphore) Release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cur -= n
+	if s.cur < 0 {
+		panic("syncx: Release de más permisos de los adquiridos")
+	}
+	s.notifyWaitersLocked()
+}
+
+// notifyWaitersLocked concede permisos a los que esperan, en orden FIFO,
+// mientras haya capacidad para satisfacer al que está al frente de la cola.
+func (s *Semaphore) notifyWaitersLocked() {
+	for {
+		front := s.waiters.Front()
+		if front == nil {
+			return
+		}
+		w := front.Value.(waiter)
+		if s.size-s.cur < w.n {
+			return
+		}
+		s.cur += w.n
+		s.waiters.Remove(front)
+		close(w.ready)
+	}
+}
@@ -0,0 +1,97 @@
+package syncx
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAcquireReleaseWithinCapacity(t *testing.T) {
+	s := NewSemaphore(2)
+	s.Acquire(2)
+	s.Release(2)
+	s.Acquire(1)
+	s.Release(1)
+}
+
+func TestTryAcquireFailsWhenFull(t *testing.T) {
+	s := NewSemaphore(1)
+	if !s.TryAcquire(1) {
+		t.Fatal("TryAcquire debería haber tenido éxito con capacidad libre")
+	}
+	if s.TryAcquire(1) {
+		t.Fatal("TryAcquire no debería tener éxito sin capacidad disponible")
+	}
+}
+
+func TestAcquireBlocksUntilRelease(t *testing.T) {
+	s := NewSemaphore(1)
+	s.Acquire(1)
+
+	acquired := make(chan struct{})
+	go func() {
+		s.Acquire(1)
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("Acquire no debería completar sin capacidad disponible")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	s.Release(1)
+
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("Acquire debería desbloquearse al liberar permisos")
+	}
+}
+
+func TestAcquireContextCancellation(t *testing.T) {
+	s := NewSemaphore(1)
+	s.Acquire(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	if err := s.AcquireContext(ctx, 1); err == nil {
+		t.Fatal("esperaba un error por cancelación de contexto")
+	}
+
+	// El permiso sigue en uso: otro Acquire inmediato no debería poder tomarlo.
+	if s.TryAcquire(1) {
+		t.Fatal("el permiso no debería estar libre: nunca se liberó")
+	}
+}
+
+func TestFIFOFairness(t *testing.T) {
+	s := NewSemaphore(1)
+	s.Acquire(1)
+
+	order := make(chan int, 2)
+	started := make(chan struct{})
+	go func() {
+		close(started)
+		s.Acquire(1)
+		order <- 1
+	}()
+	<-started
+	time.Sleep(20 * time.Millisecond) // asegurar que el primero ya está en la cola
+
+	go func() {
+		s.Acquire(1)
+		order <- 2
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	s.Release(1)
+	first := <-order
+	s.Release(1)
+	second := <-order
+
+	if first != 1 || second != 2 {
+		t.Fatalf("esperaba orden FIFO (1, 2), obtuve (%d, %d)", first, second)
+	}
+}
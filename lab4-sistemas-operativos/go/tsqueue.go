@@ -1,65 +1,29 @@
 /*
  * tsqueue.go
  *
- * Implementación en Go de una cola thread-safe (Múltiples productores y consumidores).
- * Se usa sync.Mutex y sync.Cond para proteger y coordinar acceso.
+ * Demo del problema productor-consumidor usando la cola genérica del
+ * paquete tsqueue (sync.Mutex + sync.Cond, con cierre explícito en vez de
+ * contador compartido).
  *
  * Compilar: go build tsqueue.go
- * Uso: ./tsqueue <num_producers> <num_consumers> <items_per_producer>
+ * Uso: ./tsqueue [-metrics=none|json|hist] <num_producers> <num_consumers> <items_per_producer>
  */
 
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"strconv"
 	"sync"
 	"time"
-)
-
-// ThreadSafeQueue implementada como slice dinámico
-type ThreadSafeQueue struct {
-	items []int
-	lock  sync.Mutex
-	cond  *sync.Cond
-}
-
-// Crea una nueva cola vacía
-func NewQueue() *ThreadSafeQueue {
-	q := &ThreadSafeQueue{
-		items: make([]int, 0),
-	}
-	q.cond = sync.NewCond(&q.lock)
-	return q
-}
-
-// Encola un elemento
-func (q *ThreadSafeQueue) Enqueue(item int) {
-	q.lock.Lock()
-	q.items = append(q.items, item)
-	// Señalizar que ya no está vacía
-	q.cond.Signal()
-	q.lock.Unlock()
-}
-
-// Desencola un elemento; si está vacía, espera
-func (q *ThreadSafeQueue) Dequeue() int {
-	q.lock.Lock()
-	for len(q.items) == 0 {
-		q.cond.Wait()
-	}
-	item := q.items[0]
-	q.items = q.items[1:]
-	q.lock.Unlock()
-	return item
-}
 
-var totalConsumed int
-var totalToConsume int
-var countLock sync.Mutex
+	"sincronizacion/observer"
+	"sincronizacion/tsqueue"
+)
 
-func producer(queue *ThreadSafeQueue, id int, itemsToProduce int, wg *sync.WaitGroup) {
+func producer(queue *tsqueue.Queue[int], id int, itemsToProduce int, wg *sync.WaitGroup) {
 	defer wg.Done()
 	for i := 0; i < itemsToProduce; i++ {
 		item := id*1000 + i
@@ -69,54 +33,72 @@ func producer(queue *ThreadSafeQueue, id int, itemsToProduce int, wg *sync.WaitG
 	}
 }
 
-func consumer(queue *ThreadSafeQueue, id int, wg *sync.WaitGroup) {
+func consumer(queue *tsqueue.Queue[int], id int, wg *sync.WaitGroup) {
 	defer wg.Done()
+	consumed := 0
 	for {
-		// Verificar si ya consumimos todo
-		countLock.Lock()
-		if totalConsumed >= totalToConsume {
-			countLock.Unlock()
+		// Dequeue devuelve ok=false en cuanto la cola se cierra y se drena,
+		// así que el consumidor ya no necesita sondear un contador compartido.
+		item, ok := queue.Dequeue()
+		if !ok {
 			return
 		}
-		countLock.Unlock()
-
-		item := queue.Dequeue()
-		countLock.Lock()
-		totalConsumed++
-		cur := totalConsumed
-		countLock.Unlock()
-		fmt.Printf("[Consumer %d] Dequeued item %d (consumido #%d)\n", id, item, cur)
+		consumed++
+		fmt.Printf("[Consumer %d] Dequeued item %d (consumido #%d)\n", id, item, consumed)
 		time.Sleep(150 * time.Millisecond)
 	}
 }
 
+// buildObserver construye el Observer pedido por -metrics.
+func buildObserver(kind string) observer.Observer {
+	switch kind {
+	case "json":
+		return observer.NewJSONLogger(os.Stdout)
+	case "hist":
+		return observer.NewHistogramCollector()
+	default:
+		return observer.Noop{}
+	}
+}
+
 func main() {
-	if len(os.Args) != 4 {
-		fmt.Printf("Uso: %s <num_producers> <num_consumers> <items_per_producer>\n", os.Args[0])
+	metrics := flag.String("metrics", "none", "observador de la cola: none | json | hist")
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) != 3 {
+		fmt.Printf("Uso: %s [-metrics=none|json|hist] <num_producers> <num_consumers> <items_per_producer>\n", os.Args[0])
 		os.Exit(1)
 	}
-	numProducers, _ := strconv.Atoi(os.Args[1])
-	numConsumers, _ := strconv.Atoi(os.Args[2])
-	itemsPerProducer, _ := strconv.Atoi(os.Args[3])
-
-	queue := NewQueue()
-	totalToConsume = numProducers * itemsPerProducer
+	numProducers, _ := strconv.Atoi(args[0])
+	numConsumers, _ := strconv.Atoi(args[1])
+	itemsPerProducer, _ := strconv.Atoi(args[2])
 
-	var wg sync.WaitGroup
+	obs := buildObserver(*metrics)
+	queue := tsqueue.New[int](tsqueue.WithObserver[int](obs))
 
-	// Iniciar productores
+	var producers sync.WaitGroup
 	for i := 0; i < numProducers; i++ {
-		wg.Add(1)
-		go producer(queue, i, itemsPerProducer, &wg)
+		producers.Add(1)
+		go producer(queue, i, itemsPerProducer, &producers)
 	}
 
-	// Iniciar consumidores
+	var consumers sync.WaitGroup
 	for i := 0; i < numConsumers; i++ {
-		wg.Add(1)
-		go consumer(queue, i, &wg)
+		consumers.Add(1)
+		go consumer(queue, i, &consumers)
 	}
 
-	// Esperar a que todos terminen
-	wg.Wait()
+	// Cerrar la cola en cuanto los productores terminen: despierta a todos
+	// los consumidores bloqueados y reemplaza el antiguo hack de dormir
+	// unos segundos a ciegas antes de salir.
+	producers.Wait()
+	queue.Close()
+
+	consumers.Wait()
 	fmt.Println("Todos los productores y consumidores han finalizado.")
+
+	if hist, ok := obs.(*observer.HistogramCollector); ok {
+		fmt.Print(hist.Report())
+	}
 }
@@ -0,0 +1,197 @@
+/*
+ * tsqueue.go
+ *
+ * Cola genérica thread-safe para múltiples productores y consumidores,
+ * construida sobre sync.Mutex + sync.Cond. Soporta un modo acotado
+ * (capacity > 0) donde Enqueue bloquea si la cola está llena, y un cierre
+ * (Close) que despierta a todos los que esperan y deja la cola en un
+ * estado terminal: Enqueue devuelve error y Dequeue devuelve (zero, false)
+ * una vez drenada.
+ */
+package tsqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"sincronizacion/observer"
+)
+
+// ErrClosed se devuelve al intentar encolar en una cola ya cerrada.
+var ErrClosed = errors.New("tsqueue: la cola está cerrada")
+
+// anonymousWorker se reporta al Observer en las operaciones de la cola:
+// a diferencia de un filósofo, la cola no conoce la identidad de quien
+// la llama.
+const anonymousWorker = -1
+
+// Queue es una cola FIFO genérica y segura para concurrencia.
+type Queue[T any] struct {
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	items    []T
+	capacity int // 0 significa sin límite
+	closed   bool
+	obs      observer.Observer
+}
+
+// Option configura una Queue[T] al crearla.
+type Option[T any] func(*Queue[T])
+
+// WithObserver conecta un Observer que recibe los eventos de la cola
+// (encolado, desencolado, tiempo de espera bloqueado).
+func WithObserver[T any](o observer.Observer) Option[T] {
+	return func(q *Queue[T]) { q.obs = o }
+}
+
+// New crea una cola sin límite de capacidad.
+func New[T any](opts ...Option[T]) *Queue[T] {
+	return NewBounded[T](0, opts...)
+}
+
+// NewBounded crea una cola que bloquea a los productores cuando alcanza
+// capacity elementos. capacity <= 0 equivale a una cola sin límite.
+func NewBounded[T any](capacity int, opts ...Option[T]) *Queue[T] {
+	q := &Queue[T]{capacity: capacity, obs: observer.Noop{}}
+	q.notEmpty = sync.NewCond(&q.mu)
+	q.notFull = sync.NewCond(&q.mu)
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// Enqueue agrega item al final de la cola. Si la cola es acotada y está
+// llena, bloquea hasta que haya espacio o se cierre la cola, en cuyo caso
+// devuelve ErrClosed.
+func (q *Queue[T]) Enqueue(item T) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	start := time.Now()
+	waited := false
+	for q.capacity > 0 && len(q.items) >= q.capacity && !q.closed {
+		waited = true
+		q.notFull.Wait()
+	}
+	if waited {
+		q.obs.OnBlockedWait(anonymousWorker, time.Since(start))
+	}
+	if q.closed {
+		return ErrClosed
+	}
+
+	q.items = append(q.items, item)
+	q.notEmpty.Signal()
+	q.obs.OnEnqueue(anonymousWorker, len(q.items))
+	return nil
+}
+
+// Dequeue extrae el primer elemento; si la cola está vacía, espera hasta
+// que llegue uno o hasta que la cola se cierre y drene, en cuyo caso
+// devuelve (zero, false).
+func (q *Queue[T]) Dequeue() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	start := time.Now()
+	waited := false
+	for len(q.items) == 0 && !q.closed {
+		waited = true
+		q.notEmpty.Wait()
+	}
+	if waited {
+		q.obs.OnBlockedWait(anonymousWorker, time.Since(start))
+	}
+	return q.popLocked()
+}
+
+// TryDequeue extrae el primer elemento sin bloquear; ok es false si la
+// cola está vacía en ese instante.
+func (q *Queue[T]) TryDequeue() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.popLocked()
+}
+
+// DequeueContext extrae el primer elemento, o desbloquea con ctx.Err()
+// si ctx se cancela antes de que haya un elemento disponible.
+func (q *Queue[T]) DequeueContext(ctx context.Context) (T, bool, error) {
+	// sync.Cond no conoce contextos: una goroutine auxiliar despierta el
+	// Wait cuando ctx termina, y se limpia sola al retornar esta función.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			q.mu.Lock()
+			q.notEmpty.Broadcast()
+			q.mu.Unlock()
+		case <-done:
+		}
+	}()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	start := time.Now()
+	waited := false
+	for len(q.items) == 0 && !q.closed {
+		if err := ctx.Err(); err != nil {
+			if waited {
+				q.obs.OnBlockedWait(anonymousWorker, time.Since(start))
+			}
+			var zero T
+			return zero, false, err
+		}
+		waited = true
+		q.notEmpty.Wait()
+	}
+	if waited {
+		q.obs.OnBlockedWait(anonymousWorker, time.Since(start))
+	}
+	if len(q.items) == 0 {
+		var zero T
+		return zero, false, nil // cerrada y drenada
+	}
+	item, ok := q.popLocked()
+	return item, ok, nil
+}
+
+// Len devuelve la cantidad de elementos actualmente en la cola.
+func (q *Queue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// Close cierra la cola: despierta a todos los que esperan, hace que
+// Enqueue falle con ErrClosed y que Dequeue devuelva (zero, false) en
+// cuanto se drenen los elementos pendientes. Es seguro llamarla más de
+// una vez.
+func (q *Queue[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+	q.closed = true
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+}
+
+// popLocked asume que q.mu ya está tomado.
+func (q *Queue[T]) popLocked() (T, bool) {
+	if len(q.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	item := q.items[0]
+	q.items = q.items[1:]
+	q.notFull.Signal()
+	q.obs.OnDequeue(anonymousWorker, len(q.items))
+	return item, true
+}
@@ -0,0 +1,168 @@
+package tsqueue
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEnqueueDequeueFIFO(t *testing.T) {
+	q := New[int]()
+	for i := 0; i < 5; i++ {
+		if err := q.Enqueue(i); err != nil {
+			t.Fatalf("Enqueue(%d) devolvió error inesperado: %v", i, err)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		item, ok := q.Dequeue()
+		if !ok || item != i {
+			t.Fatalf("esperaba (%d, true), obtuve (%d, %v)", i, item, ok)
+		}
+	}
+}
+
+func TestTryDequeueEmpty(t *testing.T) {
+	q := New[int]()
+	if _, ok := q.TryDequeue(); ok {
+		t.Fatal("TryDequeue en cola vacía debería devolver ok=false")
+	}
+}
+
+func TestConcurrentProducersConsumers(t *testing.T) {
+	const producers = 8
+	const itemsPerProducer = 200
+	q := New[int]()
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for i := 0; i < itemsPerProducer; i++ {
+				if err := q.Enqueue(id*itemsPerProducer + i); err != nil {
+					t.Errorf("Enqueue devolvió error inesperado: %v", err)
+				}
+			}
+		}(p)
+	}
+
+	var consumed int64
+	done := make(chan struct{})
+	go func() {
+		for {
+			_, ok := q.Dequeue()
+			if !ok {
+				close(done)
+				return
+			}
+			if atomic.AddInt64(&consumed, 1) == producers*itemsPerProducer {
+				q.Close()
+			}
+		}
+	}()
+
+	wg.Wait()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout esperando a que se consumieran todos los elementos")
+	}
+
+	if got := atomic.LoadInt64(&consumed); got != producers*itemsPerProducer {
+		t.Fatalf("se consumieron %d elementos, esperaba %d", got, producers*itemsPerProducer)
+	}
+}
+
+func TestBoundedEnqueueBlocksUntilSpace(t *testing.T) {
+	q := NewBounded[int](1)
+	if err := q.Enqueue(1); err != nil {
+		t.Fatalf("Enqueue inesperado error: %v", err)
+	}
+
+	blocked := make(chan struct{})
+	go func() {
+		if err := q.Enqueue(2); err != nil {
+			t.Errorf("Enqueue devolvió error inesperado: %v", err)
+		}
+		close(blocked)
+	}()
+
+	select {
+	case <-blocked:
+		t.Fatal("Enqueue no debería completar mientras la cola está llena")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if _, ok := q.Dequeue(); !ok {
+		t.Fatal("Dequeue debería haber devuelto un elemento")
+	}
+
+	select {
+	case <-blocked:
+	case <-time.After(time.Second):
+		t.Fatal("Enqueue debería desbloquearse al liberar espacio")
+	}
+}
+
+func TestDequeueContextCancellation(t *testing.T) {
+	q := New[int]()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	_, ok, err := q.DequeueContext(ctx)
+	if ok {
+		t.Fatal("no debería haber recibido un elemento")
+	}
+	if err == nil {
+		t.Fatal("esperaba un error por cancelación de contexto")
+	}
+}
+
+func TestDequeueContextReceivesItem(t *testing.T) {
+	q := New[int]()
+	if err := q.Enqueue(42); err != nil {
+		t.Fatalf("Enqueue inesperado error: %v", err)
+	}
+
+	item, ok, err := q.DequeueContext(context.Background())
+	if err != nil || !ok || item != 42 {
+		t.Fatalf("esperaba (42, true, nil), obtuve (%d, %v, %v)", item, ok, err)
+	}
+}
+
+func TestCloseWakesBlockedDequeue(t *testing.T) {
+	q := New[int]()
+	result := make(chan bool, 1)
+	go func() {
+		_, ok := q.Dequeue()
+		result <- ok
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	q.Close()
+
+	select {
+	case ok := <-result:
+		if ok {
+			t.Fatal("Dequeue en una cola cerrada y vacía debería devolver ok=false")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close debería despertar a los consumidores bloqueados")
+	}
+}
+
+func TestEnqueueAfterCloseFails(t *testing.T) {
+	q := New[int]()
+	q.Close()
+	if err := q.Enqueue(1); err != ErrClosed {
+		t.Fatalf("esperaba ErrClosed, obtuve %v", err)
+	}
+}
+
+func TestCloseIsIdempotent(t *testing.T) {
+	q := New[int]()
+	q.Close()
+	q.Close() // no debe bloquear ni entrar en pánico
+}